@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-portfolio/go-neuro-autoencoder/internal/autoencoder"
+	"github.com/go-portfolio/go-neuro-autoencoder/internal/mathutils"
 )
 
 func main() {
@@ -33,8 +34,9 @@ func main() {
 	// -----------------------------
 	// Обучение
 	// -----------------------------
+	trainMatrix := mathutils.FromRows(trainBatch)
 	for epoch := 0; epoch < epochs; epoch++ {
-		loss := ae.TrainStep(trainBatch, learningRate)
+		loss := ae.TrainStep(trainMatrix, learningRate)
 		if epoch%200 == 0 {
 			fmt.Printf("Epoch %d | Loss = %.6f\n", epoch, loss)
 		}
@@ -53,23 +55,32 @@ func main() {
 		fmt.Println()
 	}
 
+	// -----------------------------
+	// Обучение двухслойного автоэнкодера (8→6→4→6→8, ReLU + SGD с моментом)
+	// -----------------------------
+	fmt.Println("\n=== Обучение двухслойного автоэнкодера (SystemDS-style) ===")
+	_, _, _, _, losses := autoencoder.TrainAutoencoder2Layer(
+		trainBatch, inputSize, 6, 4, 500, 10, 0.01, 0.9, false,
+	)
+	fmt.Printf("Финальная ошибка реконструкции: %.6f\n", losses[len(losses)-1])
+
 	// -----------------------------
 	// Сохранение модели
 	// -----------------------------
-	err := ae.Save("autoencoder_weights.gob")
+	err := ae.SaveJSON("autoencoder_weights.json")
 	if err != nil {
 		fmt.Println("Error saving model:", err)
 	} else {
-		fmt.Println("Model saved to autoencoder_weights.gob")
+		fmt.Println("Model saved to autoencoder_weights.json")
 	}
 
 	// -----------------------------
-	// Создаём новый автоэнкодер и загружаем веса
+	// Загружаем веса в новый автоэнкодер
 	// -----------------------------
-	ae2 := autoencoder.NewAutoencoder(inputSize, latentSize)
-	err = ae2.Load("autoencoder_weights.gob")
+	ae2, err := autoencoder.LoadJSON("autoencoder_weights.json")
 	if err != nil {
 		fmt.Println("Error loading model:", err)
+		ae2 = autoencoder.NewAutoencoder(inputSize, latentSize)
 	} else {
 		fmt.Println("Model loaded successfully")
 	}
@@ -107,8 +118,9 @@ func generateBinaryBatch(n, size int) [][]float64 {
 
 // encodeDecode — чистая кодировка → декодировка
 func encodeDecode(ae *autoencoder.Autoencoder, x []float64) ([]float64, []float64) {
-	latent, out, _, _ := ae.Forward([][]float64{x})
-	return latent[0], binarize(out[0])
+	latent := ae.Encode(mathutils.FromRows([][]float64{x}))
+	out := ae.Decode(latent)
+	return latent.Row(0), binarize(out.Row(0))
 }
 
 // binarize — округляет выход до 0/1
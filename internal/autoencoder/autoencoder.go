@@ -1,287 +1,282 @@
 package autoencoder
 
 import (
-	"encoding/gob"
-	"os"
-	"runtime"
-	"sync"
-
 	"github.com/go-portfolio/go-neuro-autoencoder/internal/mathutils"
+	"github.com/go-portfolio/go-neuro-autoencoder/internal/optim"
 )
 
-// Autoencoder представляет простой полносвязный автоэнкодер с одним скрытым слоем.
-// Состоит из энкодера (W1, b1) и декодера (W2, b2).
+// Layer — один полносвязный слой сети: веса W, bias b и функция активации,
+// применяемая к z = xW + b.
+type Layer struct {
+	W          mathutils.Matrix
+	b          []float64
+	Activation mathutils.Activation
+}
+
+// Autoencoder представляет полносвязный автоэнкодер произвольной глубины.
+// Слои энкодера последовательно сжимают размерность, слои декодера —
+// зеркально восстанавливают исходную. Латентное представление — выход
+// последнего слоя энкодера (середина стека).
 type Autoencoder struct {
-	inputSize  int // размер входного вектора
-	latentSize int // размер скрытого (латентного) слоя
+	layers []*Layer
 
-	W1 [][]float64 // веса энкодера (вход → скрытый)
-	b1 []float64   // bias скрытого слоя
+	// optimizer, если задан через SetOptimizer, заменяет собой обычный SGD
+	// на финальном шаге обновления весов в TrainStep.
+	optimizer optim.Optimizer
 
-	W2 [][]float64 // веса декодера (скрытый → выход)
-	b2 []float64   // bias выходного слоя
+	// parallelism и pool управляют распараллеливанием вычисления градиентов
+	// по батчу — см. SetParallelism в parallel.go.
+	parallelism ParallelismConfig
+	pool        *workerPool
 }
 
-func (ae *Autoencoder) Encode(x [][]float64) [][]float64 {
-	z1 := mathutils.MatMul(x, ae.W1)  // линейная трансформация
-	a1 := mathutils.SigmoidMatrix(z1) // активация
-	return a1
+// SetOptimizer задаёт оптимизатор, который TrainStep будет использовать для
+// обновления весов и bias вместо встроенного SGD с фиксированным lr.
+func (ae *Autoencoder) SetOptimizer(o optim.Optimizer) {
+	ae.optimizer = o
 }
 
-func (ae *Autoencoder) Decode(latent [][]float64) [][]float64 {
-	z2 := mathutils.MatMul(latent, ae.W2)
-	out := mathutils.SigmoidMatrix(z2)
-	return out
+// NewDeepAutoencoder строит симметричный стек энкодер/декодер по описанию
+// layerSizes (вход и далее скрытые размеры вплоть до латентного слоя
+// включительно, например []int{8, 6, 4}) и активациям, по одной на каждый
+// слой энкодера (len(activations) == len(layerSizes)-1). Декодер зеркально
+// разворачивает те же размеры и активации в обратном порядке.
+func NewDeepAutoencoder(layerSizes []int, activations []mathutils.Activation) *Autoencoder {
+	if len(layerSizes) < 2 {
+		panic("autoencoder: layerSizes must describe at least an input and a latent size")
+	}
+	if len(activations) != len(layerSizes)-1 {
+		panic("autoencoder: activations must have exactly one entry per encoder layer")
+	}
+
+	var layers []*Layer
+
+	// Энкодер: сжимаем размерность согласно layerSizes.
+	for i := 0; i < len(layerSizes)-1; i++ {
+		layers = append(layers, newLayer(layerSizes[i], layerSizes[i+1], activations[i]))
+	}
+
+	// Декодер: те же размеры и активации в обратном порядке.
+	for i := len(layerSizes) - 1; i > 0; i-- {
+		layers = append(layers, newLayer(layerSizes[i], layerSizes[i-1], activations[i-1]))
+	}
+
+	ae := &Autoencoder{layers: layers}
+	ae.SetParallelism(DefaultParallelismConfig())
+	return ae
 }
 
-// NewAutoencoder создаёт новый автоэнкодер с заданными размерами входного и скрытого слоя.
-// Веса инициализируются случайно с помощью Xavier/He, bias инициализируются нулями.
+// NewAutoencoder создаёт классический автоэнкодер с одним скрытым (латентным)
+// слоем и сигмоидой на энкодере и декодере — частный случай NewDeepAutoencoder.
 func NewAutoencoder(inputSize, latentSize int) *Autoencoder {
-	return &Autoencoder{
-		inputSize:  inputSize,
-		latentSize: latentSize,
-
-		W1: mathutils.RandomMatrix(inputSize, latentSize),
-		b1: make([]float64, latentSize),
+	return NewDeepAutoencoder(
+		[]int{inputSize, latentSize},
+		[]mathutils.Activation{mathutils.Sigmoid{}},
+	)
+}
 
-		W2: mathutils.RandomMatrix(latentSize, inputSize),
-		b2: make([]float64, inputSize),
+func newLayer(in, out int, act mathutils.Activation) *Layer {
+	return &Layer{
+		W:          mathutils.RandomMatrix(in, out),
+		b:          make([]float64, out),
+		Activation: act,
 	}
 }
 
-// Forward выполняет прямой проход автоэнкодера для батча входных данных x.
-func (ae *Autoencoder) Forward(x [][]float64) ([][]float64, [][]float64, [][]float64, [][]float64) {
+// encoderLayers возвращает число слоёв, относящихся к энкодеру (ровно
+// половина стека — декодер всегда зеркален энкодеру).
+func (ae *Autoencoder) encoderLayers() int {
+	return len(ae.layers) / 2
+}
 
-	// --- Энкодер ---
-	// z1 = xW1 + b1
-	z1 := mathutils.AddBias(mathutils.MatMul(x, ae.W1), ae.b1)
+// Encode пропускает батч x через слои энкодера и возвращает латентное
+// представление.
+func (ae *Autoencoder) Encode(x mathutils.Matrix) mathutils.Matrix {
+	out := x
+	for i := 0; i < ae.encoderLayers(); i++ {
+		out = forwardLayer(ae.layers[i], out)
+	}
+	return out
+}
 
-	// a1 = sigmoid(z1)
-	a1 := make([][]float64, len(z1))
-	for i := range z1 {
-		a1[i] = make([]float64, len(z1[i]))
-		for j := range z1[i] {
-			a1[i][j] = mathutils.Sigmoid(z1[i][j])
-		}
+// Decode пропускает латентное представление через слои декодера и
+// возвращает восстановленный вход.
+func (ae *Autoencoder) Decode(latent mathutils.Matrix) mathutils.Matrix {
+	out := latent
+	for i := ae.encoderLayers(); i < len(ae.layers); i++ {
+		out = forwardLayer(ae.layers[i], out)
 	}
+	return out
+}
 
-	// --- Декодер ---
-	// z2 = a1W2 + b2
-	z2 := mathutils.AddBias(mathutils.MatMul(a1, ae.W2), ae.b2)
+func forwardLayer(layer *Layer, x mathutils.Matrix) mathutils.Matrix {
+	z := mathutils.AddBias(mathutils.MatMul(x, layer.W), layer.b)
+	return mathutils.ActivationMatrix(z, layer.Activation)
+}
 
-	// out = sigmoid(z2)
-	out := make([][]float64, len(z2))
-	for i := range z2 {
-		out[i] = make([]float64, len(z2[i]))
-		for j := range z2[i] {
-			out[i][j] = mathutils.Sigmoid(z2[i][j])
-		}
+// Forward выполняет полный прямой проход по всем слоям автоэнкодера для
+// батча x, кешируя предактивации (zs) и активации (as) каждого слоя —
+// они нужны backprop'у в TrainStep. as[len(as)-1] — восстановленный выход,
+// as[encoderLayers()-1] — латентное представление.
+func (ae *Autoencoder) Forward(x mathutils.Matrix) (zs, as []mathutils.Matrix) {
+	zs = make([]mathutils.Matrix, len(ae.layers))
+	as = make([]mathutils.Matrix, len(ae.layers))
+
+	input := x
+	for i, layer := range ae.layers {
+		z := mathutils.AddBias(mathutils.MatMul(input, layer.W), layer.b)
+		a := mathutils.ActivationMatrix(z, layer.Activation)
+		zs[i] = z
+		as[i] = a
+		input = a
 	}
-
-	return a1, out, z1, z2
+	return zs, as
 }
 
-// TrainStep выполняет один шаг обучения автоэнкодера на батче x с заданной скоростью обучения lr.
-// Выполняет forward pass, вычисление градиентов методом обратного распространения и обновление весов.
-// Возвращает среднеквадратичную ошибку (MSE) по батчу.
-func (ae *Autoencoder) TrainStep(x [][]float64, lr float64) float64 {
-	a1, out, z1, z2 := ae.Forward(x)
+// TrainStep выполняет один шаг обучения автоэнкодера на батче x: forward
+// pass, backprop по всем слоям и обновление весов. Если задан оптимизатор
+// (SetOptimizer), финальный шаг делегируется ему; иначе используется
+// обычный SGD с фиксированным lr. Возвращает среднеквадратичную ошибку
+// (MSE) по батчу. Эквивалентно TrainStepConfig(x, lr, nil).
+func (ae *Autoencoder) TrainStep(x mathutils.Matrix, lr float64) float64 {
+	return ae.TrainStepConfig(x, lr, nil)
+}
 
-	dOut, mse := ae.computeOutputGradient(out, x, z2)
-	dW2, db2 := ae.computeGradientsW2(a1, dOut)
-	dA1 := ae.backpropHidden(dOut, z1)
-	dW1, db1 := ae.computeGradientsW1(x, dA1)
+// TrainStepConfig — как TrainStep, но принимает TrainConfig, включающий
+// денойзинг, sparse- и contractive-регуляризацию (см. train_config.go).
+// cfg == nil эквивалентен обычному обучению на реконструкцию.
+func (ae *Autoencoder) TrainStepConfig(x mathutils.Matrix, lr float64, cfg *TrainConfig) float64 {
+	dW, db, mse := ae.backward(x, cfg)
 
-	ae.updateWeights(dW1, db1, dW2, db2, lr)
+	if ae.optimizer != nil {
+		ae.applyOptimizer(dW, db)
+	} else {
+		ae.applyGradients(dW, db, lr)
+	}
 
 	return mse
 }
 
-// computeOutputGradient вычисляет градиент ошибки на выходе и среднеквадратичную ошибку (MSE).
-func (ae *Autoencoder) computeOutputGradient(out, x, z2 [][]float64) ([][]float64, float64) {
-	mse := 0.0
-	dOut := make([][]float64, len(out))
-	for i := range out {
-		dOut[i] = make([]float64, len(out[i]))
-		for j := range out[i] {
-			diff := out[i][j] - x[i][j]
-			mse += diff * diff
-			dOut[i][j] = 2 * diff * mathutils.SigmoidDeriv(z2[i][j])
-		}
+// applyOptimizer делегирует обновление весов и bias каждого слоя
+// настроенному оптимизатору. Rows2D отдаёт срезы, указывающие на тот же
+// backing array, что и Matrix.Data, так что правки оптимизатора на месте
+// сразу видны в Layer.W.
+func (ae *Autoencoder) applyOptimizer(dW []mathutils.Matrix, db [][]float64) {
+	for l, layer := range ae.layers {
+		ae.optimizer.Step(layer.W.Rows2D(), dW[l].Rows2D())
+		ae.optimizer.StepVec(layer.b, db[l])
 	}
-	mse /= float64(len(out[0]))
-	return dOut, mse
 }
 
-// computeGradientsW2 вычисляет градиенты весов и bias декодера.
-func (ae *Autoencoder) computeGradientsW2(a1, dOut [][]float64) ([][]float64, []float64) {
-	dW2 := make([][]float64, ae.latentSize)
-	for i := range dW2 {
-		dW2[i] = make([]float64, ae.inputSize)
+// backward выполняет forward pass и обратное распространение ошибки по всем
+// слоям, возвращая градиенты весов/bias каждого слоя и MSE по батчу.
+// cfg (может быть nil) включает денойзинг входа и sparse/contractive
+// регуляризацию латентного слоя и весов энкодера — см. train_config.go.
+func (ae *Autoencoder) backward(x mathutils.Matrix, cfg *TrainConfig) (dW []mathutils.Matrix, db [][]float64, mse float64) {
+	input := x
+	if cfg.isDenoising() {
+		input = corrupt(x, cfg)
 	}
-	db2 := make([]float64, ae.inputSize)
 
-	for i := 0; i < len(a1); i++ {
-		for j := 0; j < ae.inputSize; j++ {
-			db2[j] += dOut[i][j]
-			for k := 0; k < ae.latentSize; k++ {
-				dW2[k][j] += a1[i][k] * dOut[i][j]
-			}
-		}
-	}
-	return dW2, db2
-}
+	zs, as := ae.Forward(input)
+	n := len(ae.layers)
+	out := as[n-1]
 
-// backpropHidden выполняет обратное распространение ошибки через скрытый слой.
-func (ae *Autoencoder) backpropHidden(dOut, z1 [][]float64) [][]float64 {
-	dA1 := make([][]float64, len(dOut))
-	for i := range dA1 {
-		dA1[i] = make([]float64, ae.latentSize)
-	}
+	dW = make([]mathutils.Matrix, n)
+	db = make([][]float64, n)
 
-	var wg sync.WaitGroup
-	numWorkers := runtime.NumCPU() // или len(dOut) если батч меньше
-
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go func(i int) {
-			defer wg.Done()
-			for j := 0; j < ae.latentSize; j++ {
-				sum := 0.0
-				for k := 0; k < ae.inputSize; k++ {
-					sum += dOut[i][k] * ae.W2[j][k]
-				}
-				dA1[i][j] = sum * mathutils.SigmoidDeriv(z1[i][j])
-			}
-		}(i)
+	// dA — градиент по активации (до производной f'(z)) последнего слоя.
+	// Ошибка реконструкции всегда считается относительно чистого x, даже
+	// если на вход сети подавался зашумлённый input (денойзинг).
+	dA := mathutils.NewMatrix(out.Rows, out.Cols)
+	for i := 0; i < out.Rows; i++ {
+		for j := 0; j < out.Cols; j++ {
+			diff := out.At(i, j) - x.At(i, j)
+			mse += diff * diff
+			dA.Set(i, j, 2*diff)
+		}
 	}
+	mse /= float64(out.Rows * out.Cols)
 
-	wg.Wait() // ждём, пока все горутины завершатся
-	return dA1
-}
+	latentIdx := ae.encoderLayers() - 1
 
-// Параллельные вычисления выполняются по батчу и по нейронам.
-func (ae *Autoencoder) computeGradientsW1(x, dA1 [][]float64) ([][]float64, []float64) {
-	dW1 := make([][]float64, ae.inputSize)
-	for i := range dW1 {
-		dW1[i] = make([]float64, ae.latentSize)
-	}
-	db1 := make([]float64, ae.latentSize)
+	for l := n - 1; l >= 0; l-- {
+		if cfg.isSparse() && l == latentIdx {
+			addSparsityGradient(dA, as[l], cfg)
+		}
 
-	numWorkers := runtime.NumCPU()
-	wg := sync.WaitGroup{}
-	chunkSize := (len(x) + numWorkers - 1) / numWorkers
+		delta := mathutils.NewMatrix(dA.Rows, dA.Cols)
+		for i := 0; i < dA.Rows; i++ {
+			for j := 0; j < dA.Cols; j++ {
+				delta.Set(i, j, dA.At(i, j)*ae.layers[l].Activation.Deriv(zs[l].At(i, j)))
+			}
+		}
 
-	for w := 0; w < numWorkers; w++ {
-		start := w * chunkSize
-		end := start + chunkSize
-		if end > len(x) {
-			end = len(x)
+		var layerInput mathutils.Matrix
+		if l == 0 {
+			layerInput = input
+		} else {
+			layerInput = as[l-1]
 		}
 
-		wg.Add(1)
-		go func(start, end int) {
-			defer wg.Done()
-			localDW := make([][]float64, ae.inputSize)
-			for i := range localDW {
-				localDW[i] = make([]float64, ae.latentSize)
-			}
-			localDB := make([]float64, ae.latentSize)
-
-			for i := start; i < end; i++ {
-				for j := 0; j < ae.latentSize; j++ {
-					localDB[j] += dA1[i][j]
-					for k := 0; k < ae.inputSize; k++ {
-						localDW[k][j] += x[i][k] * dA1[i][j]
-					}
-				}
-			}
+		dW[l], db[l] = ae.gradWeightsParallel(layerInput, delta)
 
-			// Суммируем локальные градиенты в глобальные
-			for i := 0; i < ae.inputSize; i++ {
-				for j := 0; j < ae.latentSize; j++ {
-					dW1[i][j] += localDW[i][j]
-				}
-			}
-			for j := 0; j < ae.latentSize; j++ {
-				db1[j] += localDB[j]
-			}
-		}(start, end)
+		if cfg.isContractive() && l == 0 {
+			addContractivePenalty(dW[l], ae.layers[0], layerInput, as[0], cfg)
+		}
+
+		if l > 0 {
+			dA = backpropThroughWeights(delta, ae.layers[l].W)
+		}
 	}
 
-	wg.Wait()
-	return dW1, db1
+	return dW, db, mse
 }
 
-// updateWeights обновляет веса и bias автоэнкодера с использованием градиентов и скорости обучения lr.
-
-func (ae *Autoencoder) updateWeights(dW1 [][]float64, db1 []float64, dW2 [][]float64, db2 []float64, lr float64) {
-	var wg sync.WaitGroup
-
-	// Обновляем W1 параллельно
-	for i := 0; i < ae.inputSize; i++ {
-		wg.Add(1)
-		go func(i int) {
-			defer wg.Done()
-			for j := 0; j < ae.latentSize; j++ {
-				ae.W1[i][j] -= lr * dW1[i][j]
+// gradWeights вычисляет градиенты весов и bias одного слоя по входу layer и
+// дельте ошибки на его выходе.
+func gradWeights(input, delta mathutils.Matrix) (mathutils.Matrix, []float64) {
+	dW := mathutils.NewMatrix(input.Cols, delta.Cols)
+	db := make([]float64, delta.Cols)
+
+	for i := 0; i < input.Rows; i++ {
+		for j := 0; j < delta.Cols; j++ {
+			d := delta.At(i, j)
+			db[j] += d
+			for k := 0; k < input.Cols; k++ {
+				dW.Set(k, j, dW.At(k, j)+input.At(i, k)*d)
 			}
-		}(i)
-	}
-
-	// Обновляем b1 параллельно
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for j := 0; j < ae.latentSize; j++ {
-			ae.b1[j] -= lr * db1[j]
 		}
-	}()
-
-	// Обновляем W2 параллельно
-	for i := 0; i < ae.latentSize; i++ {
-		wg.Add(1)
-		go func(i int) {
-			defer wg.Done()
-			for j := 0; j < ae.inputSize; j++ {
-				ae.W2[i][j] -= lr * dW2[i][j]
-			}
-		}(i)
 	}
-
-	// Обновляем b2 параллельно
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for j := 0; j < ae.inputSize; j++ {
-			ae.b2[j] -= lr * db2[j]
-		}
-	}()
-
-	// Ждём завершения всех горутин
-	wg.Wait()
+	return dW, db
 }
 
-// Save сохраняет веса и bias автоэнкодера в файл
-func (ae *Autoencoder) Save(filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
+// backpropThroughWeights переносит дельту ошибки со входа текущего слоя на
+// выход предыдущего через веса W текущего слоя, без применения производной
+// активации — её накладывает вызывающий код (backward), т.к. к ней может
+// быть предварительно добавлен градиент регуляризации (sparsity).
+func backpropThroughWeights(delta, W mathutils.Matrix) mathutils.Matrix {
+	out := mathutils.NewMatrix(delta.Rows, W.Rows)
+	for i := 0; i < delta.Rows; i++ {
+		for j := 0; j < W.Rows; j++ {
+			sum := 0.0
+			for k := 0; k < delta.Cols; k++ {
+				sum += delta.At(i, k) * W.At(j, k)
+			}
+			out.Set(i, j, sum)
+		}
 	}
-	defer file.Close()
-
-	encoder := gob.NewEncoder(file)
-	return encoder.Encode(ae)
+	return out
 }
 
-// Load загружает веса и bias из файла
-func (ae *Autoencoder) Load(filename string) error {
-	file, err := os.Open(filename)
-	if err != nil {
-		return err
+// applyGradients обновляет веса и bias всех слоёв обычным SGD: W -= lr * dW.
+func (ae *Autoencoder) applyGradients(dW []mathutils.Matrix, db [][]float64, lr float64) {
+	for l, layer := range ae.layers {
+		for i := range layer.W.Data {
+			layer.W.Data[i] -= lr * dW[l].Data[i]
+		}
+		for j := range layer.b {
+			layer.b[j] -= lr * db[l][j]
+		}
 	}
-	defer file.Close()
-
-	decoder := gob.NewDecoder(file)
-	return decoder.Decode(ae)
 }
@@ -0,0 +1,225 @@
+package autoencoder
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SaveNPZ сохраняет автоэнкодер как .npz-архив (обычный zip): по паре
+// W<i>.npy/b<i>.npy на слой плюс meta.json с архитектурой и именами
+// активаций, которые npy-формат не умеет хранить. Файл читается в Python
+// через numpy.load без какого-либо Go-кода.
+func (ae *Autoencoder) SaveNPZ(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zw := zip.NewWriter(file)
+	mf := ae.toModelFile()
+
+	for i := range mf.Weights {
+		rows, cols := mf.Architecture[i], mf.Architecture[i+1]
+
+		wEntry, err := zw.Create(fmt.Sprintf("W%d.npy", i))
+		if err != nil {
+			return err
+		}
+		if err := writeNPY(wEntry, mf.Weights[i], []int{rows, cols}); err != nil {
+			return err
+		}
+
+		bEntry, err := zw.Create(fmt.Sprintf("b%d.npy", i))
+		if err != nil {
+			return err
+		}
+		if err := writeNPY(bEntry, mf.Biases[i], []int{cols}); err != nil {
+			return err
+		}
+	}
+
+	metaEntry, err := zw.Create("meta.json")
+	if err != nil {
+		return err
+	}
+	meta := struct {
+		Architecture []int
+		Activations  []string
+	}{mf.Architecture, mf.Activations}
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	if _, err := metaEntry.Write(metaBytes); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// LoadNPZ загружает автоэнкодер, сохранённый SaveNPZ.
+func LoadNPZ(filename string) (*Autoencoder, error) {
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var meta struct {
+		Architecture []int
+		Activations  []string
+	}
+	weights := map[int][]float64{}
+	biases := map[int][]float64{}
+
+	for _, f := range r.File {
+		switch {
+		case f.Name == "meta.json":
+			data, err := readZipFile(f)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(data, &meta); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(f.Name, "W") && strings.HasSuffix(f.Name, ".npy"):
+			idx, err := npyLayerIndex(f.Name, "W")
+			if err != nil {
+				return nil, err
+			}
+			data, err := readNPYFile(f)
+			if err != nil {
+				return nil, err
+			}
+			weights[idx] = data
+		case strings.HasPrefix(f.Name, "b") && strings.HasSuffix(f.Name, ".npy"):
+			idx, err := npyLayerIndex(f.Name, "b")
+			if err != nil {
+				return nil, err
+			}
+			data, err := readNPYFile(f)
+			if err != nil {
+				return nil, err
+			}
+			biases[idx] = data
+		}
+	}
+
+	n := len(meta.Architecture) - 1
+	mf := ModelFile{
+		Version:      modelFileVersion,
+		Architecture: meta.Architecture,
+		Activations:  meta.Activations,
+		Weights:      make([][]float64, n),
+		Biases:       make([][]float64, n),
+	}
+	for i := 0; i < n; i++ {
+		mf.Weights[i] = weights[i]
+		mf.Biases[i] = biases[i]
+	}
+
+	return autoencoderFromModelFile(mf)
+}
+
+func npyLayerIndex(name, prefix string) (int, error) {
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".npy")
+	return strconv.Atoi(trimmed)
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func readNPYFile(f *zip.File) ([]float64, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return readNPY(rc)
+}
+
+// writeNPY пишет один массив в формате .npy v1.0 (магическое число, версия,
+// заголовок-словарь с dtype/shape, затем сырые little-endian float64).
+func writeNPY(w io.Writer, data []float64, shape []int) error {
+	shapeParts := make([]string, len(shape))
+	for i, s := range shape {
+		shapeParts[i] = strconv.Itoa(s)
+	}
+	shapeStr := strings.Join(shapeParts, ", ")
+	if len(shape) == 1 {
+		shapeStr += ","
+	}
+
+	header := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': (%s), }", shapeStr)
+
+	const preludeLen = 10 // 6 байт магии + 2 байта версии + 2 байта длины заголовка
+	padding := (16 - (preludeLen+len(header)+1)%16) % 16
+	header += strings.Repeat(" ", padding) + "\n"
+
+	if _, err := w.Write([]byte("\x93NUMPY\x01\x00")); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(header))); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, data)
+}
+
+// readNPY читает массив float64 из .npy v1.0, игнорируя shape (вызывающий
+// код уже знает ожидаемые размеры из meta.json) — поддерживает только
+// 8-байтный little-endian dtype ('<f8'), который пишет writeNPY.
+func readNPY(r io.Reader) ([]float64, error) {
+	var magic [6]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, err
+	}
+	if string(magic[:]) != "\x93NUMPY" {
+		return nil, fmt.Errorf("autoencoder: not a valid npy file")
+	}
+
+	var version [2]byte
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return nil, err
+	}
+
+	var headerLen uint16
+	if err := binary.Read(r, binary.LittleEndian, &headerLen); err != nil {
+		return nil, err
+	}
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest)%8 != 0 {
+		return nil, fmt.Errorf("autoencoder: corrupt npy payload: %d bytes not a multiple of 8", len(rest))
+	}
+
+	data := make([]float64, len(rest)/8)
+	for i := range data {
+		bits := binary.LittleEndian.Uint64(rest[i*8:])
+		data[i] = math.Float64frombits(bits)
+	}
+	return data, nil
+}
@@ -0,0 +1,225 @@
+package autoencoder
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/go-portfolio/go-neuro-autoencoder/internal/mathutils"
+)
+
+// ExportONNX записывает минимальный граф ONNX (Gemm + активация на каждый
+// слой, initializer'ы с весами, один вход и один выход), достаточный для
+// инференса в любом ONNX-рантайме. Граф кодируется вручную в protobuf
+// wire-формате (см. pbWriter ниже) — в дереве нет сгенерированного ONNX-пакета
+// или зависимости на protobuf, поэтому поддерживается только то подмножество
+// полей ModelProto/GraphProto, которое нужно для Gemm-based сетей.
+func (ae *Autoencoder) ExportONNX(path string) error {
+	sizes := fullStackSizes(ae)
+
+	var graph pbWriter
+	const (
+		graphFieldNode        = 1
+		graphFieldName        = 2
+		graphFieldInitializer = 5
+		graphFieldInput       = 11
+		graphFieldOutput      = 12
+	)
+
+	inputName := "input"
+	graph.writeMessage(graphFieldInput, buildValueInfo(inputName, int64(sizes[0])))
+
+	cur := inputName
+	for i, layer := range ae.layers {
+		wName := fmt.Sprintf("W%d", i)
+		bName := fmt.Sprintf("b%d", i)
+		gemmOut := fmt.Sprintf("gemm_%d", i)
+		actOut := fmt.Sprintf("layer_%d", i)
+
+		graph.writeMessage(graphFieldInitializer, buildTensorProto(wName, []int64{int64(layer.W.Rows), int64(layer.W.Cols)}, layer.W.Data))
+		graph.writeMessage(graphFieldInitializer, buildTensorProto(bName, []int64{int64(len(layer.b))}, layer.b))
+
+		gemmNode := buildNode("Gemm", []string{cur, wName, bName}, []string{gemmOut}, fmt.Sprintf("gemm_%d", i), nil)
+		graph.writeMessage(graphFieldNode, gemmNode)
+
+		opType, attrs := onnxActivation(layer.Activation)
+		actNode := buildNode(opType, []string{gemmOut}, []string{actOut}, fmt.Sprintf("act_%d", i), attrs)
+		graph.writeMessage(graphFieldNode, actNode)
+
+		cur = actOut
+	}
+
+	graph.writeMessage(graphFieldOutput, buildValueInfo(cur, int64(sizes[len(sizes)-1])))
+	graph.writeString(graphFieldName, "autoencoder")
+
+	var opset pbWriter
+	opset.writeInt64(2, 13) // OperatorSetIdProto.version
+
+	var model pbWriter
+	model.writeInt64(1, 7)                       // ModelProto.ir_version
+	model.writeString(2, "go-neuro-autoencoder") // ModelProto.producer_name
+	model.writeMessage(8, opset.buf)             // ModelProto.opset_import
+	model.writeMessage(7, graph.buf)             // ModelProto.graph
+
+	return os.WriteFile(path, model.buf, 0644)
+}
+
+// onnxActivation сопоставляет mathutils.Activation операции ONNX.
+func onnxActivation(act mathutils.Activation) (opType string, attrs [][]byte) {
+	switch v := act.(type) {
+	case mathutils.Tanh:
+		return "Tanh", nil
+	case mathutils.ReLU:
+		return "Relu", nil
+	case mathutils.LeakyReLU:
+		return "LeakyRelu", [][]byte{buildFloatAttr("alpha", float32(v.Alpha))}
+	case mathutils.Linear:
+		return "Identity", nil
+	default:
+		return "Sigmoid", nil
+	}
+}
+
+// --- минимальный protobuf wire-формат, нужный только для ONNX-экспорта ---
+
+// pbWriter собирает protobuf-сообщение вручную — поддерживает ровно те
+// типы полей (varint, length-delimited, вложенные сообщения), которые
+// использует ExportONNX.
+type pbWriter struct {
+	buf []byte
+}
+
+func (w *pbWriter) varint(v uint64) {
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			b |= 0x80
+		}
+		w.buf = append(w.buf, b)
+		if v == 0 {
+			return
+		}
+	}
+}
+
+func (w *pbWriter) tag(field, wireType int) {
+	w.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (w *pbWriter) writeString(field int, s string) {
+	if s == "" {
+		return
+	}
+	w.tag(field, 2)
+	w.varint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *pbWriter) writeMessage(field int, msg []byte) {
+	w.tag(field, 2)
+	w.varint(uint64(len(msg)))
+	w.buf = append(w.buf, msg...)
+}
+
+func (w *pbWriter) writeInt64(field int, v int64) {
+	if v == 0 {
+		return
+	}
+	w.tag(field, 0)
+	w.varint(uint64(v))
+}
+
+func (w *pbWriter) writeInt32(field int, v int32) {
+	w.writeInt64(field, int64(v))
+}
+
+func (w *pbWriter) writeInt64sPacked(field int, vals []int64) {
+	if len(vals) == 0 {
+		return
+	}
+	var packed pbWriter
+	for _, v := range vals {
+		packed.varint(uint64(v))
+	}
+	w.writeMessage(field, packed.buf)
+}
+
+func (w *pbWriter) writeDoublesPacked(field int, vals []float64) {
+	if len(vals) == 0 {
+		return
+	}
+	payload := make([]byte, len(vals)*8)
+	for i, v := range vals {
+		binary.LittleEndian.PutUint64(payload[i*8:], math.Float64bits(v))
+	}
+	w.writeMessage(field, payload)
+}
+
+// buildTensorProto строит onnx.TensorProto: float64 initializer с данными
+// в double_data (field 10), тип DOUBLE (11).
+func buildTensorProto(name string, dims []int64, data []float64) []byte {
+	var t pbWriter
+	t.writeInt64sPacked(1, dims) // dims
+	t.writeInt32(2, 11)          // data_type = DOUBLE
+	t.writeDoublesPacked(10, data)
+	t.writeString(8, name)
+	return t.buf
+}
+
+// buildValueInfo строит onnx.ValueInfoProto с формой ранга 2: [1, size]
+// (батч=1, затем признаки). Gemm по спецификации ONNX требует, чтобы его
+// операнды A/B были рангом 2 — одномерная форма (как было раньше) проходит
+// мимо проверки формы в onnx.checker и отклоняется конформными рантаймами
+// вроде onnxruntime.
+func buildValueInfo(name string, size int64) []byte {
+	var shape pbWriter
+	for _, d := range [2]int64{1, size} {
+		var dim pbWriter
+		dim.writeInt64(1, d)           // TensorShapeProto.Dimension.dim_value
+		shape.writeMessage(1, dim.buf) // TensorShapeProto.dim
+	}
+
+	var tensorType pbWriter
+	tensorType.writeInt32(1, 11)          // elem_type = DOUBLE
+	tensorType.writeMessage(2, shape.buf) // shape
+
+	var typ pbWriter
+	typ.writeMessage(1, tensorType.buf) // TypeProto.tensor_type
+
+	var vi pbWriter
+	vi.writeString(1, name)
+	vi.writeMessage(2, typ.buf)
+	return vi.buf
+}
+
+// buildNode строит onnx.NodeProto.
+func buildNode(opType string, inputs, outputs []string, name string, attrs [][]byte) []byte {
+	var n pbWriter
+	for _, in := range inputs {
+		n.writeString(1, in)
+	}
+	for _, out := range outputs {
+		n.writeString(2, out)
+	}
+	n.writeString(3, name)
+	n.writeString(4, opType)
+	for _, a := range attrs {
+		n.writeMessage(5, a)
+	}
+	return n.buf
+}
+
+// buildFloatAttr строит onnx.AttributeProto с единственным float-полем f
+// (field 2, 32-битный фиксированный тип) — используется для LeakyRelu.alpha.
+func buildFloatAttr(name string, v float32) []byte {
+	var a pbWriter
+	a.writeString(1, name)
+	a.tag(2, 5) // f, wire type 5 = 32-bit
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], math.Float32bits(v))
+	a.buf = append(a.buf, b[:]...)
+	a.writeInt32(20, 1) // type = FLOAT
+	return a.buf
+}
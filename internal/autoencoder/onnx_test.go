@@ -0,0 +1,216 @@
+package autoencoder
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-portfolio/go-neuro-autoencoder/internal/mathutils"
+)
+
+// pbField — одно декодированное поле protobuf-сообщения: varint-значение
+// для wire type 0, сырые байты для wire type 2 (length-delimited) и 5
+// (32-бит) — ровно то, что пишет pbWriter.
+type pbField struct {
+	num    int
+	wire   int
+	varint uint64
+	bytes  []byte
+}
+
+// parsePB разбирает protobuf-сообщение, закодированное pbWriter (только
+// wire types 0, 2 и 5 — больше pbWriter не использует). Используется
+// исключительно в тестах, чтобы проверить структуру графа, который
+// генерирует ExportONNX, не подключая внешний ONNX-пакет.
+func parsePB(data []byte) ([]pbField, error) {
+	var fields []pbField
+	i := 0
+	for i < len(data) {
+		tag, n := binary.Uvarint(data[i:])
+		if n <= 0 {
+			return nil, fmt.Errorf("bad tag varint at offset %d", i)
+		}
+		i += n
+		fieldNum := int(tag >> 3)
+		wireType := int(tag & 7)
+
+		switch wireType {
+		case 0:
+			v, n2 := binary.Uvarint(data[i:])
+			if n2 <= 0 {
+				return nil, fmt.Errorf("bad varint at offset %d", i)
+			}
+			i += n2
+			fields = append(fields, pbField{num: fieldNum, wire: 0, varint: v})
+		case 2:
+			l, n2 := binary.Uvarint(data[i:])
+			if n2 <= 0 {
+				return nil, fmt.Errorf("bad length varint at offset %d", i)
+			}
+			i += n2
+			if i+int(l) > len(data) {
+				return nil, fmt.Errorf("length-delimited field overruns message at offset %d", i)
+			}
+			fields = append(fields, pbField{num: fieldNum, wire: 2, bytes: data[i : i+int(l)]})
+			i += int(l)
+		case 5:
+			if i+4 > len(data) {
+				return nil, fmt.Errorf("32-bit field overruns message at offset %d", i)
+			}
+			fields = append(fields, pbField{num: fieldNum, wire: 5, bytes: data[i : i+4]})
+			i += 4
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d at offset %d", wireType, i)
+		}
+	}
+	return fields, nil
+}
+
+func findField(fields []pbField, num int) (pbField, bool) {
+	for _, f := range fields {
+		if f.num == num {
+			return f, true
+		}
+	}
+	return pbField{}, false
+}
+
+func findFields(fields []pbField, num int) []pbField {
+	var out []pbField
+	for _, f := range fields {
+		if f.num == num {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// shapeDims извлекает dim_value каждого TensorShapeProto.Dimension из
+// ValueInfoProto bytes (field 2 = type -> field 1 = tensor_type -> field 2
+// = shape -> repeated field 1 = dim -> field 1 = dim_value).
+func shapeDims(t *testing.T, valueInfo []byte) []int64 {
+	t.Helper()
+
+	vi, err := parsePB(valueInfo)
+	if err != nil {
+		t.Fatalf("parse ValueInfoProto: %v", err)
+	}
+	typ, ok := findField(vi, 2)
+	if !ok {
+		t.Fatalf("ValueInfoProto missing type field")
+	}
+	typFields, err := parsePB(typ.bytes)
+	if err != nil {
+		t.Fatalf("parse TypeProto: %v", err)
+	}
+	tensorType, ok := findField(typFields, 1)
+	if !ok {
+		t.Fatalf("TypeProto missing tensor_type field")
+	}
+	ttFields, err := parsePB(tensorType.bytes)
+	if err != nil {
+		t.Fatalf("parse TypeProto.Tensor: %v", err)
+	}
+	shape, ok := findField(ttFields, 2)
+	if !ok {
+		t.Fatalf("TypeProto.Tensor missing shape field")
+	}
+	shapeFields, err := parsePB(shape.bytes)
+	if err != nil {
+		t.Fatalf("parse TensorShapeProto: %v", err)
+	}
+
+	var dims []int64
+	for _, d := range findFields(shapeFields, 1) {
+		dimFields, err := parsePB(d.bytes)
+		if err != nil {
+			t.Fatalf("parse Dimension: %v", err)
+		}
+		dv, ok := findField(dimFields, 1)
+		if !ok {
+			t.Fatalf("Dimension missing dim_value")
+		}
+		dims = append(dims, int64(dv.varint))
+	}
+	return dims
+}
+
+// TestExportONNXRankTwoShapes проверяет, что вход и выход экспортированного
+// графа объявлены с рангом 2 ([1, size]), как того требует Gemm (его
+// операнды A/B не могут быть рангом 1) — рейтинг 1 проходит мимо
+// onnx.checker и отклоняется конформными рантаймами.
+func TestExportONNXRankTwoShapes(t *testing.T) {
+	ae := NewDeepAutoencoder([]int{6, 4, 2}, []mathutils.Activation{mathutils.ReLU{}, mathutils.Sigmoid{}})
+
+	path := filepath.Join(t.TempDir(), "model.onnx")
+	if err := ae.ExportONNX(path); err != nil {
+		t.Fatalf("ExportONNX: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read exported model: %v", err)
+	}
+	model, err := parsePB(data)
+	if err != nil {
+		t.Fatalf("parse ModelProto: %v", err)
+	}
+	graphField, ok := findField(model, 7)
+	if !ok {
+		t.Fatalf("ModelProto missing graph field")
+	}
+	graph, err := parsePB(graphField.bytes)
+	if err != nil {
+		t.Fatalf("parse GraphProto: %v", err)
+	}
+
+	input, ok := findField(graph, 11)
+	if !ok {
+		t.Fatalf("GraphProto missing input field")
+	}
+	output, ok := findField(graph, 12)
+	if !ok {
+		t.Fatalf("GraphProto missing output field")
+	}
+
+	wantInput := []int64{1, 6}
+	wantOutput := []int64{1, 6}
+	if got := shapeDims(t, input.bytes); !equalDims(got, wantInput) {
+		t.Fatalf("input shape = %v, want %v", got, wantInput)
+	}
+	if got := shapeDims(t, output.bytes); !equalDims(got, wantOutput) {
+		t.Fatalf("output shape = %v, want %v", got, wantOutput)
+	}
+
+	gemmCount := 0
+	for _, nodeField := range findFields(graph, 1) {
+		node, err := parsePB(nodeField.bytes)
+		if err != nil {
+			t.Fatalf("parse NodeProto: %v", err)
+		}
+		opType, ok := findField(node, 4)
+		if !ok {
+			t.Fatalf("NodeProto missing op_type field")
+		}
+		if string(opType.bytes) == "Gemm" {
+			gemmCount++
+		}
+	}
+	if wantGemm := len(ae.layers); gemmCount != wantGemm {
+		t.Fatalf("Gemm node count = %d, want %d", gemmCount, wantGemm)
+	}
+}
+
+func equalDims(got, want []int64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
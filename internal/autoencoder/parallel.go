@@ -0,0 +1,123 @@
+package autoencoder
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/go-portfolio/go-neuro-autoencoder/internal/mathutils"
+)
+
+// ParallelismConfig управляет тем, как TrainStep распределяет вычисление
+// градиентов по батчу между воркерами. На маленьких батчах overhead
+// fan-out/fan-in превышает выигрыш от параллелизма, поэтому
+// MinBatchForParallel задаёт порог, ниже которого градиенты считаются
+// последовательно в том же потоке.
+type ParallelismConfig struct {
+	Workers             int
+	MinBatchForParallel int
+}
+
+// DefaultParallelismConfig использует по одному воркеру на ядро и порог в
+// 128 примеров на батч.
+func DefaultParallelismConfig() ParallelismConfig {
+	return ParallelismConfig{Workers: runtime.NumCPU(), MinBatchForParallel: 128}
+}
+
+// workerPool — пул из N горутин, созданных один раз и переиспользуемых между
+// вызовами Run, чтобы не платить за создание горутин на каждом шаге
+// обучения.
+type workerPool struct {
+	tasks chan func()
+}
+
+func newWorkerPool(workers int) *workerPool {
+	p := &workerPool{tasks: make(chan func())}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for task := range p.tasks {
+				task()
+			}
+		}()
+	}
+	return p
+}
+
+// Run выполняет все tasks на воркерах пула и ждёт их завершения.
+func (p *workerPool) Run(tasks []func()) {
+	var wg sync.WaitGroup
+	wg.Add(len(tasks))
+	for _, task := range tasks {
+		task := task
+		p.tasks <- func() {
+			defer wg.Done()
+			task()
+		}
+	}
+	wg.Wait()
+}
+
+func (p *workerPool) close() {
+	close(p.tasks)
+}
+
+// SetParallelism задаёт число воркеров и порог батча, начиная с которого
+// TrainStep распределяет вычисление градиентов параллельно. Заменяет
+// текущий пул воркеров новым (старый останавливается).
+func (ae *Autoencoder) SetParallelism(cfg ParallelismConfig) {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if ae.pool != nil {
+		ae.pool.close()
+	}
+	ae.parallelism = cfg
+	ae.pool = newWorkerPool(cfg.Workers)
+}
+
+// gradWeightsParallel — как gradWeights, но при достаточно большом батче
+// распределяет строки input/delta по воркерам пула и суммирует частичные
+// градиенты. Ниже MinBatchForParallel считает последовательно.
+func (ae *Autoencoder) gradWeightsParallel(input, delta mathutils.Matrix) (mathutils.Matrix, []float64) {
+	rows := input.Rows
+	if ae.pool == nil || rows < ae.parallelism.MinBatchForParallel {
+		return gradWeights(input, delta)
+	}
+
+	workers := ae.parallelism.Workers
+	chunk := (rows + workers - 1) / workers
+
+	partialW := make([]mathutils.Matrix, 0, workers)
+	partialB := make([][]float64, 0, workers)
+	var tasks []func()
+
+	for start := 0; start < rows; start += chunk {
+		end := start + chunk
+		if end > rows {
+			end = rows
+		}
+
+		idx := len(partialW)
+		partialW = append(partialW, mathutils.Matrix{})
+		partialB = append(partialB, nil)
+
+		start, end := start, end
+		tasks = append(tasks, func() {
+			partialW[idx], partialB[idx] = gradWeights(input.SliceRows(start, end), delta.SliceRows(start, end))
+		})
+	}
+
+	ae.pool.Run(tasks)
+
+	dW := mathutils.NewMatrix(input.Cols, delta.Cols)
+	db := make([]float64, delta.Cols)
+	for i := range partialW {
+		for k := range dW.Data {
+			dW.Data[k] += partialW[i].Data[k]
+		}
+		for j := range db {
+			db[j] += partialB[i][j]
+		}
+	}
+
+	return dW, db
+}
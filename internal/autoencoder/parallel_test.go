@@ -0,0 +1,77 @@
+package autoencoder
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/go-portfolio/go-neuro-autoencoder/internal/mathutils"
+)
+
+func randomBatch(n, size int) mathutils.Matrix {
+	m := mathutils.NewMatrix(n, size)
+	for i := range m.Data {
+		m.Data[i] = rand.Float64()
+	}
+	return m
+}
+
+// TestGradWeightsParallelMatchesSerial проверяет, что параллельный путь
+// вычисления градиентов (gradWeightsParallel) даёт тот же результат, что и
+// последовательный gradWeights, на батче, не делящемся ровно на число
+// воркеров. Запускайте с -race, чтобы проверить отсутствие гонок данных в
+// workerPool.
+func TestGradWeightsParallelMatchesSerial(t *testing.T) {
+	ae := NewDeepAutoencoder([]int{16, 8}, []mathutils.Activation{mathutils.Sigmoid{}})
+	ae.SetParallelism(ParallelismConfig{Workers: 4, MinBatchForParallel: 1})
+
+	input := randomBatch(37, 16)
+	delta := randomBatch(37, 8)
+
+	dWSerial, dbSerial := gradWeights(input, delta)
+	dWParallel, dbParallel := ae.gradWeightsParallel(input, delta)
+
+	for i := range dWSerial.Data {
+		if math.Abs(dWSerial.Data[i]-dWParallel.Data[i]) > 1e-9 {
+			t.Fatalf("dW mismatch at %d: serial=%v parallel=%v", i, dWSerial.Data[i], dWParallel.Data[i])
+		}
+	}
+	for j := range dbSerial {
+		if math.Abs(dbSerial[j]-dbParallel[j]) > 1e-9 {
+			t.Fatalf("db mismatch at %d: serial=%v parallel=%v", j, dbSerial[j], dbParallel[j])
+		}
+	}
+}
+
+func benchmarkTrainStep(b *testing.B, batchSize int, cfg ParallelismConfig) {
+	ae := NewDeepAutoencoder([]int{64, 32}, []mathutils.Activation{mathutils.Sigmoid{}})
+	ae.SetParallelism(cfg)
+	batch := randomBatch(batchSize, 64)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ae.TrainStep(batch, 0.01)
+	}
+}
+
+func serialParallelismConfig() ParallelismConfig {
+	return ParallelismConfig{Workers: 1, MinBatchForParallel: 1 << 30}
+}
+
+// parallelParallelismConfig включает параллельный путь при любом размере
+// батча, в отличие от DefaultParallelismConfig с порогом 128.
+func parallelParallelismConfig() ParallelismConfig {
+	return ParallelismConfig{Workers: runtime.NumCPU(), MinBatchForParallel: 1}
+}
+
+func BenchmarkTrainStepSerial8(b *testing.B)   { benchmarkTrainStep(b, 8, serialParallelismConfig()) }
+func BenchmarkTrainStepParallel8(b *testing.B) { benchmarkTrainStep(b, 8, parallelParallelismConfig()) }
+func BenchmarkTrainStepSerial64(b *testing.B)  { benchmarkTrainStep(b, 64, serialParallelismConfig()) }
+func BenchmarkTrainStepParallel64(b *testing.B) {
+	benchmarkTrainStep(b, 64, parallelParallelismConfig())
+}
+func BenchmarkTrainStepSerial512(b *testing.B) { benchmarkTrainStep(b, 512, serialParallelismConfig()) }
+func BenchmarkTrainStepParallel512(b *testing.B) {
+	benchmarkTrainStep(b, 512, parallelParallelismConfig())
+}
@@ -0,0 +1,176 @@
+package autoencoder
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/go-portfolio/go-neuro-autoencoder/internal/mathutils"
+)
+
+// modelFileVersion нумерует формат ModelFile — увеличивайте при несовместимых
+// изменениях полей, чтобы Load-функции могли распознать старые файлы.
+const modelFileVersion = 1
+
+// ModelFile — версионированное, не зависящее от Go представление
+// автоэнкодера: все поля экспортированы, поэтому оно (в отличие от
+// сохранения *Autoencoder через gob напрямую) переживает encoding/gob,
+// encoding/json и может быть прочитано из Python/NumPy (см. SaveNPZ).
+// Architecture — размеры всего стека слоёв (вход, ..., выход), длиной
+// len(Weights)+1. Weights[i]/Biases[i] относятся к слою i, W уплощён
+// построчно (row-major), Activations[i] — его активация по имени.
+type ModelFile struct {
+	Version      int
+	Architecture []int
+	Activations  []string
+	Weights      [][]float64
+	Biases       [][]float64
+}
+
+// activationName возвращает строковое имя активации для ModelFile.
+// LeakyReLU с нестандартным Alpha сериализуется с именем по умолчанию —
+// нестандартный наклон при round-trip не сохраняется.
+func activationName(act mathutils.Activation) string {
+	switch act.(type) {
+	case mathutils.Tanh:
+		return "tanh"
+	case mathutils.ReLU:
+		return "relu"
+	case mathutils.LeakyReLU:
+		return "leaky_relu"
+	case mathutils.Linear:
+		return "linear"
+	default:
+		return "sigmoid"
+	}
+}
+
+func activationByName(name string) mathutils.Activation {
+	switch name {
+	case "tanh":
+		return mathutils.Tanh{}
+	case "relu":
+		return mathutils.ReLU{}
+	case "leaky_relu":
+		return mathutils.LeakyReLU{Alpha: mathutils.DefaultLeakyReLUAlpha}
+	case "linear":
+		return mathutils.Linear{}
+	default:
+		return mathutils.Sigmoid{}
+	}
+}
+
+// fullStackSizes возвращает размеры всего стека слоёв: вход, затем выход
+// каждого слоя по порядку — ровно то, что ModelFile.Architecture хранит.
+func fullStackSizes(ae *Autoencoder) []int {
+	sizes := make([]int, 0, len(ae.layers)+1)
+	sizes = append(sizes, ae.layers[0].W.Rows)
+	for _, layer := range ae.layers {
+		sizes = append(sizes, layer.W.Cols)
+	}
+	return sizes
+}
+
+// toModelFile снимает снимок весов автоэнкодера в переносимый ModelFile.
+func (ae *Autoencoder) toModelFile() ModelFile {
+	mf := ModelFile{
+		Version:      modelFileVersion,
+		Architecture: fullStackSizes(ae),
+		Activations:  make([]string, len(ae.layers)),
+		Weights:      make([][]float64, len(ae.layers)),
+		Biases:       make([][]float64, len(ae.layers)),
+	}
+	for i, layer := range ae.layers {
+		mf.Activations[i] = activationName(layer.Activation)
+		mf.Weights[i] = append([]float64(nil), layer.W.Data...)
+		mf.Biases[i] = append([]float64(nil), layer.b...)
+	}
+	return mf
+}
+
+// autoencoderFromModelFile восстанавливает *Autoencoder из ModelFile,
+// проверяя согласованность размеров по пути.
+func autoencoderFromModelFile(mf ModelFile) (*Autoencoder, error) {
+	if len(mf.Architecture) < 2 {
+		return nil, fmt.Errorf("autoencoder: model file architecture must have at least 2 sizes, got %d", len(mf.Architecture))
+	}
+
+	n := len(mf.Architecture) - 1
+	if len(mf.Activations) != n || len(mf.Weights) != n || len(mf.Biases) != n {
+		return nil, fmt.Errorf("autoencoder: model file layer count mismatch: architecture implies %d layers", n)
+	}
+
+	layers := make([]*Layer, n)
+	for i := 0; i < n; i++ {
+		rows, cols := mf.Architecture[i], mf.Architecture[i+1]
+		if len(mf.Weights[i]) != rows*cols {
+			return nil, fmt.Errorf("autoencoder: layer %d: expected %d weights, got %d", i, rows*cols, len(mf.Weights[i]))
+		}
+		if len(mf.Biases[i]) != cols {
+			return nil, fmt.Errorf("autoencoder: layer %d: expected %d biases, got %d", i, cols, len(mf.Biases[i]))
+		}
+
+		layers[i] = &Layer{
+			W:          mathutils.Matrix{Rows: rows, Cols: cols, Data: append([]float64(nil), mf.Weights[i]...)},
+			b:          append([]float64(nil), mf.Biases[i]...),
+			Activation: activationByName(mf.Activations[i]),
+		}
+	}
+
+	ae := &Autoencoder{layers: layers}
+	ae.SetParallelism(DefaultParallelismConfig())
+	return ae, nil
+}
+
+// SaveJSON сохраняет автоэнкодер как версионированный ModelFile в формате JSON.
+func (ae *Autoencoder) SaveJSON(filename string) error {
+	data, err := json.MarshalIndent(ae.toModelFile(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+// LoadJSON загружает автоэнкодер, сохранённый SaveJSON.
+func LoadJSON(filename string) (*Autoencoder, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var mf ModelFile
+	if err := json.Unmarshal(data, &mf); err != nil {
+		return nil, err
+	}
+	return autoencoderFromModelFile(mf)
+}
+
+// SaveGob сохраняет автоэнкодер как версионированный ModelFile в формате
+// gob. В отличие от старого Save (до этого коммита), кодирует ModelFile, а
+// не сам *Autoencoder — его поля не экспортированы, и gob их молча
+// игнорировал, производя пустые файлы.
+func (ae *Autoencoder) SaveGob(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return gob.NewEncoder(file).Encode(ae.toModelFile())
+}
+
+// LoadGob загружает автоэнкодер, сохранённый SaveGob.
+func LoadGob(filename string) (*Autoencoder, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var mf ModelFile
+	if err := gob.NewDecoder(file).Decode(&mf); err != nil {
+		return nil, err
+	}
+	return autoencoderFromModelFile(mf)
+}
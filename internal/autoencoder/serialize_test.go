@@ -0,0 +1,52 @@
+package autoencoder
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-portfolio/go-neuro-autoencoder/internal/mathutils"
+)
+
+// TestSaveLoadRoundTrip проверяет, что реконструкция после Save/Load
+// совпадает с реконструкцией до сохранения, для всех трёх форматов.
+func TestSaveLoadRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	batch := randomBatch(3, 6)
+
+	cases := []struct {
+		name string
+		save func(*Autoencoder, string) error
+		load func(string) (*Autoencoder, error)
+	}{
+		{"json", (*Autoencoder).SaveJSON, LoadJSON},
+		{"gob", (*Autoencoder).SaveGob, LoadGob},
+		{"npz", (*Autoencoder).SaveNPZ, LoadNPZ},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ae := NewDeepAutoencoder([]int{6, 4, 2}, []mathutils.Activation{mathutils.ReLU{}, mathutils.Sigmoid{}})
+			want := ae.Decode(ae.Encode(batch))
+
+			path := filepath.Join(dir, "model."+tc.name)
+			if err := tc.save(ae, path); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+
+			loaded, err := tc.load(path)
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+
+			got := loaded.Decode(loaded.Encode(batch))
+			if got.Rows != want.Rows || got.Cols != want.Cols {
+				t.Fatalf("shape mismatch: want %dx%d got %dx%d", want.Rows, want.Cols, got.Rows, got.Cols)
+			}
+			for i := range want.Data {
+				if want.Data[i] != got.Data[i] {
+					t.Fatalf("reconstruction mismatch at %d: want %v got %v", i, want.Data[i], got.Data[i])
+				}
+			}
+		})
+	}
+}
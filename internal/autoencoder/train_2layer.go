@@ -0,0 +1,72 @@
+package autoencoder
+
+import (
+	"math/rand"
+
+	"github.com/go-portfolio/go-neuro-autoencoder/internal/mathutils"
+	"github.com/go-portfolio/go-neuro-autoencoder/internal/optim"
+)
+
+// TrainAutoencoder2Layer обучает двухслойный автоэнкодер (вход → H1 → H2 →
+// H1 → вход) мини-батч SGD с моментом, по образцу билт-ина
+// autoencoder_2layer из SystemDS: ReLU на обоих скрытых слоях энкодера и
+// декодера, Linear (или Sigmoid, если sigmoidOutput == true) на выходном
+// слое. Данные перемешиваются перед каждой эпохой. Возвращает веса всех
+// четырёх слоёв (encoder: w1, w2; decoder: w3, w4) и среднюю ошибку
+// реконструкции за каждую эпоху.
+func TrainAutoencoder2Layer(
+	data [][]float64,
+	inputSize, h1, h2 int,
+	epochs, batchSize int,
+	lr, momentum float64,
+	sigmoidOutput bool,
+) (w1, w2, w3, w4 mathutils.Matrix, losses []float64) {
+	ae := NewDeepAutoencoder(
+		[]int{inputSize, h1, h2},
+		[]mathutils.Activation{mathutils.ReLU{}, mathutils.ReLU{}},
+	)
+
+	if sigmoidOutput {
+		ae.layers[len(ae.layers)-1].Activation = mathutils.Sigmoid{}
+	} else {
+		ae.layers[len(ae.layers)-1].Activation = mathutils.Linear{}
+	}
+
+	ae.SetOptimizer(optim.NewSGD(lr, momentum, false))
+
+	losses = make([]float64, 0, epochs)
+
+	for epoch := 0; epoch < epochs; epoch++ {
+		shuffled := shuffleBatch(data)
+		epochLoss := 0.0
+		numBatches := 0
+
+		for start := 0; start < len(shuffled); start += batchSize {
+			end := start + batchSize
+			if end > len(shuffled) {
+				end = len(shuffled)
+			}
+
+			batch := mathutils.FromRows(shuffled[start:end])
+			epochLoss += ae.TrainStep(batch, lr)
+			numBatches++
+		}
+
+		losses = append(losses, epochLoss/float64(numBatches))
+	}
+
+	return ae.layers[0].W, ae.layers[1].W, ae.layers[2].W, ae.layers[3].W, losses
+}
+
+// shuffleBatch возвращает копию data со случайно переставленными строками
+// (алгоритм Фишера-Йейтса), не изменяя исходный срез.
+func shuffleBatch(data [][]float64) [][]float64 {
+	shuffled := make([][]float64, len(data))
+	copy(shuffled, data)
+
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return shuffled
+}
@@ -0,0 +1,199 @@
+package autoencoder
+
+import (
+	"math/rand"
+
+	"github.com/go-portfolio/go-neuro-autoencoder/internal/mathutils"
+)
+
+// TrainConfig включает дополнительные режимы обучения поверх обычной
+// реконструкции: денойзинг входа, разреженность (sparsity) латентного
+// слоя и контрактивную регуляризацию весов энкодера. Строится через
+// NewTrainConfig().With...(...), режимы комбинируются свободно.
+type TrainConfig struct {
+	denoising  bool
+	useMask    bool
+	noiseSigma float64
+	maskProb   float64
+
+	sparse         bool
+	sparsityTarget float64 // ρ — целевая средняя активация
+	sparsityWeight float64 // β — вес штрафа KL(ρ || ρ̂_j)
+
+	contractive       bool
+	contractiveLambda float64 // λ — вес штрафа Якобиана
+}
+
+// NewTrainConfig создаёт пустой TrainConfig (обычное обучение на
+// реконструкцию, без регуляризации).
+func NewTrainConfig() *TrainConfig {
+	return &TrainConfig{}
+}
+
+// WithDenoising включает денойзинг гауссовым шумом: перед forward pass к
+// каждому входу добавляется шум N(0, sigma), но ошибка считается
+// относительно чистого входа.
+func (c *TrainConfig) WithDenoising(sigma float64) *TrainConfig {
+	c.denoising = true
+	c.useMask = false
+	c.noiseSigma = sigma
+	return c
+}
+
+// WithMaskingNoise включает денойзинг бинарной маской: каждый вход
+// обнуляется независимо с вероятностью p (Bernoulli masking).
+func (c *TrainConfig) WithMaskingNoise(p float64) *TrainConfig {
+	c.denoising = true
+	c.useMask = true
+	c.maskProb = p
+	return c
+}
+
+// WithSparsity включает штраф разреженности: KL-дивергенцию между целевой
+// активацией rho и средней по батчу активацией ρ̂_j каждого нейрона
+// латентного слоя, с весом beta.
+func (c *TrainConfig) WithSparsity(rho, beta float64) *TrainConfig {
+	c.sparse = true
+	c.sparsityTarget = rho
+	c.sparsityWeight = beta
+	return c
+}
+
+// WithContractive включает контрактивную регуляризацию: штраф на
+// Фробениусову норму Якобиана активаций первого слоя энкодера по его
+// входу, с весом lambda. Штраф накладывается только на первый слой (при
+// одном слое энкодера это и есть латентное представление).
+func (c *TrainConfig) WithContractive(lambda float64) *TrainConfig {
+	c.contractive = true
+	c.contractiveLambda = lambda
+	return c
+}
+
+func (c *TrainConfig) isDenoising() bool {
+	return c != nil && c.denoising
+}
+
+func (c *TrainConfig) isSparse() bool {
+	return c != nil && c.sparse
+}
+
+func (c *TrainConfig) isContractive() bool {
+	return c != nil && c.contractive
+}
+
+// corrupt возвращает зашумлённую копию x согласно режиму денойзинга cfg
+// (гауссов шум либо Bernoulli-маскирование).
+func corrupt(x mathutils.Matrix, cfg *TrainConfig) mathutils.Matrix {
+	out := mathutils.NewMatrix(x.Rows, x.Cols)
+	copy(out.Data, x.Data)
+
+	if cfg.useMask {
+		for i := range out.Data {
+			if rand.Float64() < cfg.maskProb {
+				out.Data[i] = 0
+			}
+		}
+		return out
+	}
+
+	for i := range out.Data {
+		out.Data[i] += rand.NormFloat64() * cfg.noiseSigma
+	}
+	return out
+}
+
+// addSparsityGradient добавляет к dA (градиенту по активации латентного
+// слоя a) производную штрафа KL(ρ || ρ̂_j) по каждому нейрону j:
+//
+//	d/da_j^(n) [β·KL(ρ||ρ̂_j)] = β/m · (-ρ/ρ̂_j + (1-ρ)/(1-ρ̂_j))
+//
+// где ρ̂_j — средняя активация нейрона j по батчу из m примеров. Эта
+// производная одинакова для всех примеров n, т.к. ρ̂_j сама является
+// средним по батчу.
+func addSparsityGradient(dA, a mathutils.Matrix, cfg *TrainConfig) {
+	rho := cfg.sparsityTarget
+	beta := cfg.sparsityWeight
+	m := float64(a.Rows)
+
+	rhoHat := make([]float64, a.Cols)
+	for j := 0; j < a.Cols; j++ {
+		sum := 0.0
+		for i := 0; i < a.Rows; i++ {
+			sum += a.At(i, j)
+		}
+		rhoHat[j] = sum / m
+	}
+
+	for j := 0; j < a.Cols; j++ {
+		grad := beta * (-rho/rhoHat[j] + (1-rho)/(1-rhoHat[j])) / m
+		for i := 0; i < dA.Rows; i++ {
+			dA.Set(i, j, dA.At(i, j)+grad)
+		}
+	}
+}
+
+// addContractivePenalty добавляет к dW (градиенту весов первого слоя
+// энкодера) аналитический градиент контрактивного штрафа
+//
+//	Ω = λ · Σ_n Σ_j (a_j^(n)(1-a_j^(n)))² · Σ_i W[i,j]²
+//
+// усреднённого по батчу из m примеров input. Предполагает, что слой
+// использует сигмоиду (a(1-a) — её производная); для других активаций
+// штраф не добавляется.
+func addContractivePenalty(dW mathutils.Matrix, layer *Layer, input, a mathutils.Matrix, cfg *TrainConfig) {
+	if _, ok := layer.Activation.(mathutils.Sigmoid); !ok {
+		return
+	}
+
+	lambda := cfg.contractiveLambda
+	m := float64(a.Rows)
+	rows, cols := layer.W.Rows, layer.W.Cols
+
+	// Σ_i W[i,j]^2 — сумма квадратов весов, входящих в нейрон j.
+	sumWSq := make([]float64, cols)
+	for j := 0; j < cols; j++ {
+		s := 0.0
+		for i := 0; i < rows; i++ {
+			w := layer.W.At(i, j)
+			s += w * w
+		}
+		sumWSq[j] = s
+	}
+
+	// h_j = mean_n (a_j^(n)(1-a_j^(n)))^2
+	h := make([]float64, cols)
+	for j := 0; j < cols; j++ {
+		sum := 0.0
+		for n := 0; n < a.Rows; n++ {
+			d := a.At(n, j) * (1 - a.At(n, j))
+			sum += d * d
+		}
+		h[j] = sum / m
+	}
+
+	// cross[i][j] = mean_n (a_j^(n)(1-a_j^(n)))²·(1-2a_j^(n))·input_i^(n)
+	//
+	// d/dW[i,j] (a_j(1-a_j))² = 2·(a_j(1-a_j))·(1-2a_j)·da_j/dW[i,j]
+	//                         = 2·(a_j(1-a_j))²·(1-2a_j)·input_i
+	// (da_j/dW[i,j] = a_j(1-a_j)·input_i), so the coefficient needs g_j
+	// squared, not just g_j — missing this factor produced a gradient
+	// that didn't match the finite-difference check.
+	cross := mathutils.NewMatrix(rows, cols)
+	for n := 0; n < a.Rows; n++ {
+		for j := 0; j < cols; j++ {
+			aj := a.At(n, j)
+			g := aj * (1 - aj)
+			coef := g * g * (1 - 2*aj)
+			for i := 0; i < rows; i++ {
+				cross.Set(i, j, cross.At(i, j)+coef*input.At(n, i))
+			}
+		}
+	}
+
+	for j := 0; j < cols; j++ {
+		for i := 0; i < rows; i++ {
+			grad := lambda * (2*layer.W.At(i, j)*h[j] + 2*sumWSq[j]*cross.At(i, j)/m)
+			dW.Set(i, j, dW.At(i, j)+grad)
+		}
+	}
+}
@@ -2,25 +2,102 @@ package mathutils
 
 import "math"
 
-// Сигмоида — функция активации
-func Sigmoid(x float64) float64 {
-	return 1.0 / (1.0 + math.Exp(-x)) // формула σ(x) = 1/(1+e^-x)
+// Activation описывает функцию активации нейрона и её производную.
+// Forward вычисляет a = f(z), Deriv вычисляет df/dz в той же точке z
+// (z — значение до применения активации, как оно хранится в кеше прямого прохода).
+type Activation interface {
+	Forward(z float64) float64
+	Deriv(z float64) float64
 }
 
-// Производная сигмоиды для обратного прохода
-func SigmoidDeriv(x float64) float64 {
-	s := Sigmoid(x)    // сначала вычисляем сигмоиду
-	return s * (1 - s) // её производная: s*(1-s)
+// Sigmoid — логистическая функция активации σ(z) = 1/(1+e^-z).
+type Sigmoid struct{}
+
+func (Sigmoid) Forward(z float64) float64 {
+	return 1.0 / (1.0 + math.Exp(-z))
+}
+
+func (s Sigmoid) Deriv(z float64) float64 {
+	a := s.Forward(z)
+	return a * (1 - a)
+}
+
+// Tanh — гиперболический тангенс.
+type Tanh struct{}
+
+func (Tanh) Forward(z float64) float64 {
+	return math.Tanh(z)
+}
+
+func (Tanh) Deriv(z float64) float64 {
+	t := math.Tanh(z)
+	return 1 - t*t
+}
+
+// ReLU — выпрямленная линейная функция активации.
+type ReLU struct{}
+
+func (ReLU) Forward(z float64) float64 {
+	if z > 0 {
+		return z
+	}
+	return 0
+}
+
+func (ReLU) Deriv(z float64) float64 {
+	if z > 0 {
+		return 1
+	}
+	return 0
+}
+
+// DefaultLeakyReLUAlpha — наклон при z<0, который подразумевается там, где
+// нужно какое-то значение по умолчанию (например, при восстановлении
+// LeakyReLU из ModelFile, который не хранит Alpha — см. activationByName).
+const DefaultLeakyReLUAlpha = 0.01
+
+// LeakyReLU — ReLU с наклоном Alpha при z<0. Alpha используется буквально:
+// LeakyReLU{Alpha: 0} — валидный (хоть и бессмысленный) вариант с нулевым
+// наклоном, а не "наклон не задан" — нулевое значение раньше неявно
+// подменялось на DefaultLeakyReLUAlpha, из-за чего LeakyReLU{Alpha: 0}
+// молча вело себя как LeakyReLU{Alpha: 0.01}. Используйте
+// LeakyReLU{Alpha: DefaultLeakyReLUAlpha}, если нужно именно значение
+// по умолчанию.
+type LeakyReLU struct {
+	Alpha float64
+}
+
+func (r LeakyReLU) Forward(z float64) float64 {
+	if z > 0 {
+		return z
+	}
+	return r.Alpha * z
+}
+
+func (r LeakyReLU) Deriv(z float64) float64 {
+	if z > 0 {
+		return 1
+	}
+	return r.Alpha
+}
+
+// Linear — тождественная активация f(z) = z, обычно используется на выходном
+// слое регрессионных автоэнкодеров.
+type Linear struct{}
+
+func (Linear) Forward(z float64) float64 {
+	return z
+}
+
+func (Linear) Deriv(float64) float64 {
+	return 1
 }
 
-// Сигмоида для матрицы
-func SigmoidMatrix(m [][]float64) [][]float64 {
-	out := make([][]float64, len(m))
-	for i := range m {
-		out[i] = make([]float64, len(m[i]))
-		for j := range m[i] {
-			out[i][j] = Sigmoid(m[i][j])
-		}
+// ActivationMatrix применяет функцию активации поэлементно к каждой матрице.
+func ActivationMatrix(m Matrix, act Activation) Matrix {
+	out := NewMatrix(m.Rows, m.Cols)
+	for i, v := range m.Data {
+		out.Data[i] = act.Forward(v)
 	}
 	return out
 }
@@ -0,0 +1,54 @@
+//go:build !blas
+
+package mathutils
+
+// matMulBlockSize — размер блока для кэш-блочного умножения матриц. Подобран
+// по бенчмаркам (см. matmul_bench_test.go) как компромисс между L1/L2-кэшем
+// на типичном x86_64.
+const matMulBlockSize = 64
+
+// MatMulDense — чистый Go путь ("purego"): кэш-блочное ijk-умножение матриц
+// прямо по плоскому представлению Matrix.Data, без аллокаций на строку.
+// При сборке с тегом `blas` эта функция заменяется на обёртку над
+// gonum.org/v1/gonum/blas/blas64.Gemm — см. blas_gonum.go.
+func MatMulDense(a, b Matrix) Matrix {
+	out := NewMatrix(a.Rows, b.Cols)
+
+	for ii := 0; ii < a.Rows; ii += matMulBlockSize {
+		iEnd := minInt(ii+matMulBlockSize, a.Rows)
+
+		for kk := 0; kk < a.Cols; kk += matMulBlockSize {
+			kEnd := minInt(kk+matMulBlockSize, a.Cols)
+
+			for jj := 0; jj < b.Cols; jj += matMulBlockSize {
+				jEnd := minInt(jj+matMulBlockSize, b.Cols)
+
+				for i := ii; i < iEnd; i++ {
+					rowOut := i * out.Cols
+					rowA := i * a.Cols
+
+					for k := kk; k < kEnd; k++ {
+						aik := a.Data[rowA+k]
+						if aik == 0 {
+							continue
+						}
+
+						rowB := k * b.Cols
+						for j := jj; j < jEnd; j++ {
+							out.Data[rowOut+j] += aik * b.Data[rowB+j]
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return out
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,53 @@
+package mathutils
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// matMulNaive — исходный неблочный тройной цикл умножения матриц, оставлен
+// только для сравнения в бенчмарках с блочным MatMulDense.
+func matMulNaive(a, b Matrix) Matrix {
+	out := NewMatrix(a.Rows, b.Cols)
+	for i := 0; i < a.Rows; i++ {
+		for j := 0; j < b.Cols; j++ {
+			sum := 0.0
+			for k := 0; k < a.Cols; k++ {
+				sum += a.At(i, k) * b.At(k, j)
+			}
+			out.Set(i, j, sum)
+		}
+	}
+	return out
+}
+
+func randomBenchMatrix(n int) Matrix {
+	m := NewMatrix(n, n)
+	for i := range m.Data {
+		m.Data[i] = rand.Float64()
+	}
+	return m
+}
+
+func benchmarkMatMulNaive(b *testing.B, n int) {
+	a := randomBenchMatrix(n)
+	bm := randomBenchMatrix(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		matMulNaive(a, bm)
+	}
+}
+
+func benchmarkMatMulDense(b *testing.B, n int) {
+	a := randomBenchMatrix(n)
+	bm := randomBenchMatrix(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MatMulDense(a, bm)
+	}
+}
+
+func BenchmarkMatMulNaive512(b *testing.B)  { benchmarkMatMulNaive(b, 512) }
+func BenchmarkMatMulDense512(b *testing.B)  { benchmarkMatMulDense(b, 512) }
+func BenchmarkMatMulNaive1024(b *testing.B) { benchmarkMatMulNaive(b, 1024) }
+func BenchmarkMatMulDense1024(b *testing.B) { benchmarkMatMulDense(b, 1024) }
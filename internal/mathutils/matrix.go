@@ -5,53 +5,95 @@ import (
 	"math/rand" // генератор случайных чисел
 )
 
-// Генерация матрицы случайных весов (распределение нормальное)
-func RandomMatrix(rows, cols int) [][]float64 {
-	scale := math.Sqrt(2.0 / float64(rows+cols)) // Xavier для сигмоиды
-
-	m := make([][]float64, rows) // создаём массив строк
-	for i := range m {
-		m[i] = make([]float64, cols) // создаём столбцы
-		for j := range m[i] {
-			m[i][j] = rand.NormFloat64() * scale // маленькие случайные веса
-		}
+// Matrix — плотная матрица в плоском (row-major) представлении. Хранение в
+// одном срезе вместо [][]float64 убирает аллокацию на каждую строку и
+// позволяет BLAS-совместимым бэкендам (см. blas.go) работать с Data
+// напрямую.
+type Matrix struct {
+	Rows, Cols int
+	Data       []float64 // длина Rows*Cols, строка i занимает Data[i*Cols : (i+1)*Cols]
+}
+
+// NewMatrix создаёт нулевую матрицу заданного размера.
+func NewMatrix(rows, cols int) Matrix {
+	return Matrix{Rows: rows, Cols: cols, Data: make([]float64, rows*cols)}
+}
+
+// At возвращает элемент (i, j).
+func (m Matrix) At(i, j int) float64 {
+	return m.Data[i*m.Cols+j]
+}
+
+// Set записывает элемент (i, j).
+func (m Matrix) Set(i, j int, v float64) {
+	m.Data[i*m.Cols+j] = v
+}
+
+// Row возвращает строку i как срез, указывающий на тот же backing array —
+// изменения среза изменяют саму матрицу.
+func (m Matrix) Row(i int) []float64 {
+	start := i * m.Cols
+	return m.Data[start : start+m.Cols]
+}
+
+// Rows2D представляет матрицу как [][]float64, где каждая строка — срез,
+// указывающий на тот же backing array, что и Data (без копирования
+// элементов, в отличие от настоящей матрицы строк). Используется на
+// границе с кодом, которому удобнее работать построчно (например,
+// internal/optim).
+func (m Matrix) Rows2D() [][]float64 {
+	rows := make([][]float64, m.Rows)
+	for i := range rows {
+		start := i * m.Cols
+		rows[i] = m.Data[start : start+m.Cols : start+m.Cols]
 	}
-	return m // возвращаем матрицу
+	return rows
 }
 
-// Матричное умножение A*B
-func MatMul(a [][]float64, b [][]float64) [][]float64 {
-	rowsA := len(a)    // число строк A
-	colsA := len(a[0]) // число столбцов A
-	colsB := len(b[0]) // число столбцов B
+// SliceRows возвращает подматрицу строк [start, end), указывающую на тот же
+// backing array, что и Data — без копирования.
+func (m Matrix) SliceRows(start, end int) Matrix {
+	return Matrix{Rows: end - start, Cols: m.Cols, Data: m.Data[start*m.Cols : end*m.Cols]}
+}
 
-	// создаём матрицу результата
-	out := make([][]float64, rowsA)
-	for i := range out {
-		out[i] = make([]float64, colsB)
+// FromRows строит Matrix из [][]float64, копируя данные построчно.
+func FromRows(rows [][]float64) Matrix {
+	if len(rows) == 0 {
+		return Matrix{}
+	}
+	m := NewMatrix(len(rows), len(rows[0]))
+	for i, row := range rows {
+		copy(m.Row(i), row)
 	}
+	return m
+}
 
-	// тройной цикл умножения матриц
-	for i := 0; i < rowsA; i++ {
-		for j := 0; j < colsB; j++ {
-			sum := 0.0
-			for k := 0; k < colsA; k++ {
-				sum += a[i][k] * b[k][j] // умножение и суммирование
-			}
-			out[i][j] = sum // записываем результат
-		}
+// RandomMatrix генерирует матрицу случайных весов с нормальным
+// распределением, масштабированную по Xavier (подходит для сигмоиды/tanh).
+func RandomMatrix(rows, cols int) Matrix {
+	scale := math.Sqrt(2.0 / float64(rows+cols))
+
+	m := NewMatrix(rows, cols)
+	for i := range m.Data {
+		m.Data[i] = rand.NormFloat64() * scale
 	}
-	return out
+	return m
 }
 
-// Добавление смещения (bias) к каждому ряду матрицы
-func AddBias(x [][]float64, b []float64) [][]float64 {
-	out := make([][]float64, len(x)) // создаём новую матрицу
-	for i := range x {
-		out[i] = make([]float64, len(x[i]))
-		for j := range x[i] {
-			out[i][j] = x[i][j] + b[j] // прибавляем соответствующий bias
+// AddBias прибавляет вектор bias b к каждой строке матрицы x.
+func AddBias(x Matrix, b []float64) Matrix {
+	out := NewMatrix(x.Rows, x.Cols)
+	for i := 0; i < x.Rows; i++ {
+		for j := 0; j < x.Cols; j++ {
+			out.Set(i, j, x.At(i, j)+b[j])
 		}
 	}
 	return out
 }
+
+// MatMul перемножает матрицы A*B. Фактическая реализация выбирается
+// на этапе сборки: блочный чистый Go (по умолчанию) или BLAS-бэкенд
+// gonum при сборке с тегом `blas` — см. blas.go.
+func MatMul(a, b Matrix) Matrix {
+	return MatMulDense(a, b)
+}
@@ -0,0 +1,46 @@
+package optim
+
+import "math"
+
+// AdaGrad накапливает сумму квадратов градиентов за всё время обучения и
+// делит на неё шаг обучения: cache += grad^2, params -= LR*grad/(sqrt(cache)+Eps).
+type AdaGrad struct {
+	LR  float64
+	Eps float64
+
+	cache map[*float64][]float64
+}
+
+// NewAdaGrad создаёт AdaGrad с заданными гиперпараметрами. Типичное
+// значение по умолчанию: eps=1e-8.
+func NewAdaGrad(lr, eps float64) *AdaGrad {
+	return &AdaGrad{
+		LR:    lr,
+		Eps:   eps,
+		cache: make(map[*float64][]float64),
+	}
+}
+
+func (o *AdaGrad) StepVec(params, grads []float64) {
+	key := paramKey(params)
+	if key == nil {
+		return
+	}
+
+	c, ok := o.cache[key]
+	if !ok {
+		c = make([]float64, len(params))
+		o.cache[key] = c
+	}
+
+	for i := range params {
+		c[i] += grads[i] * grads[i]
+		params[i] -= o.LR * grads[i] / (math.Sqrt(c[i]) + o.Eps)
+	}
+}
+
+func (o *AdaGrad) Step(params, grads [][]float64) {
+	for i := range params {
+		o.StepVec(params[i], grads[i])
+	}
+}
@@ -0,0 +1,70 @@
+package optim
+
+import "math"
+
+// Adam — адаптивный оптимизатор с моментами первого и второго порядка
+// (Kingma & Ba, 2014). Хранит m/v и счётчик шагов t отдельно на каждый
+// параметр.
+type Adam struct {
+	LR    float64
+	Beta1 float64
+	Beta2 float64
+	Eps   float64
+
+	m map[*float64][]float64
+	v map[*float64][]float64
+	t map[*float64]int
+}
+
+// NewAdam создаёt Adam с заданными гиперпараметрами. Типичные значения по
+// умолчанию: beta1=0.9, beta2=0.999, eps=1e-8.
+func NewAdam(lr, beta1, beta2, eps float64) *Adam {
+	return &Adam{
+		LR:    lr,
+		Beta1: beta1,
+		Beta2: beta2,
+		Eps:   eps,
+		m:     make(map[*float64][]float64),
+		v:     make(map[*float64][]float64),
+		t:     make(map[*float64]int),
+	}
+}
+
+func (o *Adam) StepVec(params, grads []float64) {
+	key := paramKey(params)
+	if key == nil {
+		return
+	}
+
+	m, ok := o.m[key]
+	if !ok {
+		m = make([]float64, len(params))
+		o.m[key] = m
+	}
+	v, ok := o.v[key]
+	if !ok {
+		v = make([]float64, len(params))
+		o.v[key] = v
+	}
+
+	o.t[key]++
+	t := float64(o.t[key])
+	biasCorr1 := 1 - math.Pow(o.Beta1, t)
+	biasCorr2 := 1 - math.Pow(o.Beta2, t)
+
+	for i := range params {
+		m[i] = o.Beta1*m[i] + (1-o.Beta1)*grads[i]
+		v[i] = o.Beta2*v[i] + (1-o.Beta2)*grads[i]*grads[i]
+
+		mHat := m[i] / biasCorr1
+		vHat := v[i] / biasCorr2
+
+		params[i] -= o.LR * mHat / (math.Sqrt(vHat) + o.Eps)
+	}
+}
+
+func (o *Adam) Step(params, grads [][]float64) {
+	for i := range params {
+		o.StepVec(params[i], grads[i])
+	}
+}
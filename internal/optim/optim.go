@@ -0,0 +1,22 @@
+// Package optim предоставляет алгоритмы обновления весов (оптимизаторы),
+// используемые взамен жёстко зашитого в autoencoder SGD-апдейта.
+package optim
+
+// Optimizer обновляет параметры сети на месте по их градиентам. Step
+// используется для матриц весов слоя, StepVec — для векторов bias.
+// Реализации хранят собственное состояние на параметр (момент, скользящие
+// средние и т.п.), ключом служит указатель на первый элемент переданного
+// среза — так один Optimizer может обслуживать несколько слоёв сразу.
+type Optimizer interface {
+	Step(params, grads [][]float64)
+	StepVec(params, grads []float64)
+}
+
+// paramKey возвращает стабильный ключ состояния оптимизатора для среза
+// параметров. Пустые срезы не имеют состояния.
+func paramKey(params []float64) *float64 {
+	if len(params) == 0 {
+		return nil
+	}
+	return &params[0]
+}
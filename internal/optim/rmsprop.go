@@ -0,0 +1,48 @@
+package optim
+
+import "math"
+
+// RMSProp делит градиент на скользящее среднеквадратичное его значений:
+// cache = Rho*cache + (1-Rho)*grad^2, params -= LR*grad/(sqrt(cache)+Eps).
+type RMSProp struct {
+	LR  float64
+	Rho float64
+	Eps float64
+
+	cache map[*float64][]float64
+}
+
+// NewRMSProp создаёт RMSProp с заданными гиперпараметрами. Типичное
+// значение по умолчанию: rho=0.9, eps=1e-8.
+func NewRMSProp(lr, rho, eps float64) *RMSProp {
+	return &RMSProp{
+		LR:    lr,
+		Rho:   rho,
+		Eps:   eps,
+		cache: make(map[*float64][]float64),
+	}
+}
+
+func (o *RMSProp) StepVec(params, grads []float64) {
+	key := paramKey(params)
+	if key == nil {
+		return
+	}
+
+	c, ok := o.cache[key]
+	if !ok {
+		c = make([]float64, len(params))
+		o.cache[key] = c
+	}
+
+	for i := range params {
+		c[i] = o.Rho*c[i] + (1-o.Rho)*grads[i]*grads[i]
+		params[i] -= o.LR * grads[i] / (math.Sqrt(c[i]) + o.Eps)
+	}
+}
+
+func (o *RMSProp) Step(params, grads [][]float64) {
+	for i := range params {
+		o.StepVec(params[i], grads[i])
+	}
+}
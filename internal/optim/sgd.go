@@ -0,0 +1,50 @@
+package optim
+
+// SGD — стохастический градиентный спуск с моментом и опциональным
+// ускорением Нестерова: velocity = Momentum*velocity - LR*grad,
+// params += velocity (или params += Momentum*velocity - LR*grad для Nesterov).
+type SGD struct {
+	LR       float64
+	Momentum float64
+	Nesterov bool
+
+	velocity map[*float64][]float64
+}
+
+// NewSGD создаёт оптимизатор SGD с заданными параметрами.
+func NewSGD(lr, momentum float64, nesterov bool) *SGD {
+	return &SGD{
+		LR:       lr,
+		Momentum: momentum,
+		Nesterov: nesterov,
+		velocity: make(map[*float64][]float64),
+	}
+}
+
+func (o *SGD) StepVec(params, grads []float64) {
+	key := paramKey(params)
+	if key == nil {
+		return
+	}
+
+	v, ok := o.velocity[key]
+	if !ok {
+		v = make([]float64, len(params))
+		o.velocity[key] = v
+	}
+
+	for i := range params {
+		v[i] = o.Momentum*v[i] - o.LR*grads[i]
+		if o.Nesterov {
+			params[i] += o.Momentum*v[i] - o.LR*grads[i]
+		} else {
+			params[i] += v[i]
+		}
+	}
+}
+
+func (o *SGD) Step(params, grads [][]float64) {
+	for i := range params {
+		o.StepVec(params[i], grads[i])
+	}
+}